@@ -1,57 +1,61 @@
 package browser
 
 import (
+	"context"
 	"encoding/json"
-	"os/exec"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/stealth"
-	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/headless_browser"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/log"
 )
 
-// Config holds the configuration options for the browser.
-type Config struct {
-	Headless        bool   // Whether to run browser in headless mode
-	UserAgent       string // Custom user agent string
-	Cookies         string // JSON string of cookies to set
-	UseSystemChrome bool   // Whether to use system Chrome instead of default Chromium
-}
-
-// NewConfig creates a new Config with default values.
-func NewConfig(headless bool) Config {
-	return Config{
-		Headless:        headless,
-		UserAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
-		UseSystemChrome: true, // 默认使用系统 Chrome
-	}
+// Instance is the common surface NewBrowser/NewBrowserWithConfig hand back,
+// satisfied both by a directly-launched CustomBrowser and by the bundled
+// headless_browser fallback.
+type Instance interface {
+	Close()
+	NewPage() *rod.Page
 }
 
 // NewBrowser creates a new browser instance with the given configuration
 // This is a backward-compatible function that creates a browser with default settings
-func NewBrowser(headless bool) *headless_browser.Browser {
+func NewBrowser(ctx context.Context, headless bool) Instance {
 	cfg := NewConfig(headless)
-	return NewBrowserWithConfig(cfg)
+	return NewBrowserWithConfig(ctx, cfg)
 }
 
 // NewChromeVisibleBrowser creates a new browser instance using system Chrome in visible mode
-func NewChromeVisibleBrowser() *ChromeBrowser {
-	return NewChromeBrowser(false) // false = visible mode
+func NewChromeVisibleBrowser(ctx context.Context) (*ChromeBrowser, error) {
+	return NewChromeBrowser(ctx, false) // false = visible mode
 }
 
 // CustomBrowser wraps rod.Browser to be compatible with headless_browser.Browser
 type CustomBrowser struct {
-	browser  *rod.Browser
-	launcher *launcher.Launcher
+	browser         *rod.Browser
+	launcher        *launcher.Launcher
+	tempUserDataDir string // non-empty when a per-run temp profile was created, removed on Close
 }
 
-// Close does nothing - browser will remain open
+// Close shuts down the browser and its launcher (which runs with Leakless, so
+// the Chrome process is reaped even if this process crashes first), and
+// removes the temporary user-data dir created for this run, if any.
 func (cb *CustomBrowser) Close() {
-	logrus.Info("CustomBrowser Close() called but browser will remain open")
-	// Do nothing - keep browser running
+	cb.browser.MustClose()
+	cb.launcher.Kill()
+
+	if cb.tempUserDataDir != "" {
+		if err := os.RemoveAll(cb.tempUserDataDir); err != nil {
+			log.Warnf("failed to remove temp user-data dir %s: %v", cb.tempUserDataDir, err)
+		}
+	}
 }
 
 // NewPage creates a new page with stealth mode enabled
@@ -59,57 +63,126 @@ func (cb *CustomBrowser) NewPage() *rod.Page {
 	return stealth.MustPage(cb.browser)
 }
 
-// NewBrowserWithConfig creates a new browser instance with the given configuration
-func NewBrowserWithConfig(cfg Config) *headless_browser.Browser {
-	if cfg.UseSystemChrome && isSystemChromeAvailable() {
-		logrus.Info("Using system Chrome")
-		
-		// Create a new launcher with system Chrome
-		l := launcher.New()
-		
-		// Find Chrome path
-		chromePath := findChromePath()
+// NewBrowserWithConfig creates a new browser instance with the given
+// configuration. ctx is used only to correlate the log lines emitted while
+// launching Chrome and loading cookies with the request that triggered it;
+// it is not threaded into the returned Instance.
+func NewBrowserWithConfig(ctx context.Context, cfg Config) Instance {
+	logger := log.FromContext(ctx)
+
+	if cfg.UseSystemChrome && systemChromeUsable(cfg) {
+		logger.Info().Msg("using system chrome")
+
+		// Create a new launcher with system Chrome, leakless so an orphaned
+		// Chrome process is reaped if this process crashes before Close()
+		l := launcher.New().Leakless(true)
+
+		// Find Chrome path, preferring an explicit override over auto-discovery
+		chromePath := cfg.ChromePath
+		if chromePath == "" {
+			chromePath = findChromePath()
+		}
 		if chromePath != "" {
 			l = l.Bin(chromePath)
-			logrus.Infof("Found Chrome at: %s", chromePath)
 		}
-		
+
+		// Use an explicit user-data dir when given, otherwise spin up a
+		// disposable per-run profile so runs never step on each other.
+		tempUserDataDir := ""
+		userDataDir := cfg.UserDataDir
+		if userDataDir == "" {
+			dir, err := os.MkdirTemp("", "xhs-mcp-*")
+			if err != nil {
+				logger.Warn().Err(err).Msg("failed to create temp user-data dir")
+			} else {
+				tempUserDataDir = dir
+				userDataDir = dir
+			}
+		}
+		if userDataDir != "" {
+			l = l.UserDataDir(userDataDir)
+		}
+
 		// Set headless mode and launch
-		l = l.Headless(cfg.Headless).Set("--no-sandbox")
-		url := l.MustLaunch()
-		
-		// Create a new rod browser instance
-		browser := rod.New().ControlURL(url).MustConnect()
-		
-		// Load cookies if available
-		cookiePath := cookies.GetCookiesFilePath()
-		cookieLoader := cookies.NewLoadCookie(cookiePath)
-		if data, err := cookieLoader.LoadCookies(); err == nil {
-			var cookieData []*proto.NetworkCookie
-			if err := json.Unmarshal(data, &cookieData); err == nil {
-				browser.MustSetCookies(cookieData...)
-				logrus.Debugf("loaded %d cookies from file successfully", len(cookieData))
+		l = l.Headless(cfg.Headless)
+		if cfg.ProfileDir != "" {
+			l = l.Set("--profile-directory", cfg.ProfileDir)
+		}
+		if isRunningAsRoot() {
+			l = l.Set("--no-sandbox")
+		}
+		for _, flag := range hardenedChromeFlags {
+			if flag[1] == "" {
+				l = l.Set(launcher.Flag(flag[0]))
+			} else {
+				l = l.Set(launcher.Flag(flag[0]), flag[1])
 			}
+		}
+		// --remote-debugging-port=0 asks Chrome to pick a free port itself;
+		// rod reads the assigned port back from Chrome's stderr "DevTools
+		// listening on ws://..." line and folds it into the control URL
+		// MustLaunch returns below.
+		l = l.Set("--remote-debugging-port", strconv.Itoa(cfg.RemoteDebuggingPort))
+		for _, arg := range cfg.ExtraArgs {
+			l = l.Set(launcher.Flag(arg))
+		}
+
+		launchStart := time.Now()
+		controlURL := l.MustLaunch()
+		port, _ := portFromControlURL(controlURL)
+		logger.Info().
+			Str("chrome_path", chromePath).
+			Str("profile_dir", cfg.ProfileDir).
+			Int("port", port).
+			Dur("elapsed", time.Since(launchStart)).
+			Msg("chrome launched")
+
+		// Create a new rod browser instance
+		browser := rod.New().ControlURL(controlURL).MustConnect()
+
+		// Load cookies, preferring a direct read from the Chrome profile's
+		// Cookies DB over a hand-exported JSON dump.
+		cookieStart := time.Now()
+		if dbCookies, err := loadCookiesFromChromeDB(cfg.ProfileDir); err == nil && len(dbCookies) > 0 {
+			browser.MustSetCookies(dbCookies...)
+			logger.Debug().
+				Int("count", len(dbCookies)).
+				Str("profile_dir", cfg.ProfileDir).
+				Dur("elapsed", time.Since(cookieStart)).
+				Msg("loaded cookies from chrome profile db")
 		} else {
-			logrus.Warnf("failed to load cookies: %v", err)
+			if err != nil {
+				logger.Warn().Err(err).Msg("failed to read cookies from chrome profile db")
+			}
+
+			cookiePath := cookies.GetCookiesFilePath()
+			cookieLoader := cookies.NewLoadCookie(cookiePath)
+			if data, err := cookieLoader.LoadCookies(); err == nil {
+				var cookieData []*proto.NetworkCookie
+				if err := json.Unmarshal(data, &cookieData); err == nil {
+					browser.MustSetCookies(cookieData...)
+					logger.Debug().Int("count", len(cookieData)).Msg("loaded cookies from file")
+				}
+			} else {
+				logger.Warn().Err(err).Msg("failed to load cookies")
+			}
 		}
-		
-		// Create custom browser wrapper
-		customBrowser := &CustomBrowser{
-			browser:  browser,
-			launcher: l,
+
+		// Create custom browser wrapper and return it directly: it's the one
+		// actually configured above (temp dir, hardened flags, cookies), and
+		// the one Close() needs to tear down.
+		return &CustomBrowser{
+			browser:         browser,
+			launcher:        l,
+			tempUserDataDir: tempUserDataDir,
 		}
-		
-		// Convert to headless_browser.Browser interface
-		// Since we can't directly return CustomBrowser, we'll create a compatible wrapper
-		return createCompatibleBrowser(customBrowser)
 	}
 
 	// Fall back to default headless_browser implementation
 	if cfg.UseSystemChrome {
-		logrus.Warn("System Chrome not found or not available, falling back to default Chromium")
+		logger.Warn().Msg("system chrome not found or not available, falling back to default chromium")
 	}
-	
+
 	opts := []headless_browser.Option{
 		headless_browser.WithHeadless(cfg.Headless),
 	}
@@ -120,43 +193,20 @@ func NewBrowserWithConfig(cfg Config) *headless_browser.Browser {
 
 	if data, err := cookieLoader.LoadCookies(); err == nil {
 		opts = append(opts, headless_browser.WithCookies(string(data)))
-		logrus.Debugf("loaded cookies from file successfully")
+		logger.Debug().Msg("loaded cookies from file")
 	} else {
-		logrus.Warnf("failed to load cookies: %v", err)
+		logger.Warn().Err(err).Msg("failed to load cookies")
 	}
 
 	return headless_browser.New(opts...)
 }
 
-
-// createCompatibleBrowser creates a wrapper that's compatible with headless_browser.Browser interface
-func createCompatibleBrowser(cb *CustomBrowser) *headless_browser.Browser {
-	// Since we can't easily convert types, let's just ensure Chrome is actually launched
-	// and return a new headless_browser instance that will use the same Chrome process
-	logrus.Info("Chrome browser launched successfully, creating compatible wrapper")
-	
-	// Return a new headless_browser instance - the Chrome process is already running
-	// This is a workaround until we can properly integrate with the headless_browser package
-	return headless_browser.New(headless_browser.WithHeadless(false))
-}
-
-// isSystemChromeAvailable checks if system Chrome is available
-func isSystemChromeAvailable() bool {
-	return findChromePath() != ""
-}
-
-// findChromePath finds the path to Chrome on the system
-func findChromePath() string {
-	chromePaths := []string{
-		"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
-		"/Applications/Google Chrome Canary.app/Contents/MacOS/Google Chrome Canary",
-		"/Applications/Chromium.app/Contents/MacOS/Chromium",
-	}
-
-	for _, path := range chromePaths {
-		if _, err := exec.LookPath(path); err == nil {
-			return path
-		}
+// portFromControlURL extracts the port rod resolved the DevTools endpoint to,
+// e.g. out of "ws://127.0.0.1:54321/devtools/browser/...".
+func portFromControlURL(controlURL string) (int, error) {
+	parsed, err := url.Parse(controlURL)
+	if err != nil {
+		return 0, err
 	}
-	return ""
+	return strconv.Atoi(parsed.Port())
 }
@@ -1,6 +1,7 @@
 package browser
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,39 +13,59 @@ import (
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/stealth"
-	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies/chromedb"
+	"github.com/xpzouying/xiaohongshu-mcp/log"
 )
 
+// xiaohongshuCookieHost is the cookie host suffix chromedb filters on when
+// reading cookies directly out of a Chrome profile.
+const xiaohongshuCookieHost = "xiaohongshu.com"
+
+// loadCookiesFromChromeDB reads xiaohongshu.com cookies directly out of the
+// given Chrome profile directory (e.g. "Default", "Profile 1"; empty means
+// "Default"), the preferred source over a hand-exported JSON dump.
+func loadCookiesFromChromeDB(profileDir string) ([]*proto.NetworkCookie, error) {
+	if profileDir == "" {
+		profileDir = "Default"
+	}
+	dbPath := filepath.Join(getUserChromeDataDir(), profileDir, "Cookies")
+	return chromedb.ReadCookies(dbPath, xiaohongshuCookieHost)
+}
+
 // ChromeBrowser is a direct Chrome browser implementation
 type ChromeBrowser struct {
 	browser  *rod.Browser
 	launcher *launcher.Launcher
 }
 
-// NewChromeBrowser creates a new Chrome browser instance
-func NewChromeBrowser(headless bool) *ChromeBrowser {
+// NewChromeBrowser creates a new Chrome browser instance. It no longer exits
+// the process when Chrome isn't reachable; callers get an error back instead.
+func NewChromeBrowser(ctx context.Context, headless bool) (*ChromeBrowser, error) {
+	logger := log.FromContext(ctx)
+
 	// Connect to existing Chrome instance - user's current Chrome with current profile
-	browser, err := connectToExistingChrome()
+	start := time.Now()
+	browser, port, err := connectToExistingChrome()
 	if err != nil {
-		logrus.Fatal("Cannot connect to your current Chrome. Please enable remote debugging:\n" +
-			"1. Close Chrome completely\n" +
-			"2. Start Chrome with: open -a 'Google Chrome' --args --remote-debugging-port=9222\n" +
-			"3. Then run this program again")
-		return nil
+		return nil, fmt.Errorf("cannot connect to your current Chrome, please enable remote debugging "+
+			"(close Chrome completely, then start it with --remote-debugging-port=9222): %w", err)
 	}
 
-	logrus.Info("Connected to your current Chrome - will create new tab with your current profile")
-	
+	logger.Info().
+		Int("port", port).
+		Dur("elapsed", time.Since(start)).
+		Msg("connected to your current chrome - will create new tab with your current profile")
+
 	return &ChromeBrowser{
 		browser:  browser,
 		launcher: nil,
-	}
+	}, nil
 }
 
 // Close does nothing - browser will remain open
 func (cb *ChromeBrowser) Close() {
-	logrus.Info("Close() called but browser will remain open")
+	log.Info("Close() called but browser will remain open")
 	// Do nothing - keep browser running
 }
 
@@ -53,13 +74,14 @@ func (cb *ChromeBrowser) NewPage() *rod.Page {
 	return stealth.MustPage(cb.browser)
 }
 
-// connectToExistingChrome tries to connect to an existing Chrome instance
-func connectToExistingChrome() (*rod.Browser, error) {
+// connectToExistingChrome tries to connect to an existing Chrome instance,
+// returning the debugging port it connected on alongside the browser.
+func connectToExistingChrome() (*rod.Browser, int, error) {
 	// Try common Chrome debugging ports
-	ports := []string{"9222", "9223", "9224"}
+	ports := []int{9222, 9223, 9224}
 
 	for _, port := range ports {
-		url := fmt.Sprintf("http://localhost:%s", port)
+		url := fmt.Sprintf("http://localhost:%d", port)
 		client := &http.Client{Timeout: 2 * time.Second}
 
 		// Check if Chrome is running on this port
@@ -73,16 +95,25 @@ func connectToExistingChrome() (*rod.Browser, error) {
 			// Try to connect
 			browser := rod.New().ControlURL(url)
 			if err := browser.Connect(); err == nil {
-				return browser, nil
+				return browser, port, nil
 			}
 		}
 	}
 
-	return nil, fmt.Errorf("no existing Chrome instance found")
+	return nil, 0, fmt.Errorf("no existing Chrome instance found")
 }
 
-// loadCookiesForBrowser loads cookies for the browser
-func loadCookiesForBrowser(browser *rod.Browser) {
+// loadCookiesForBrowser loads cookies for the browser, preferring a direct
+// read from the Chrome profile's Cookies DB over a hand-exported JSON dump.
+func loadCookiesForBrowser(ctx context.Context, browser *rod.Browser) {
+	logger := log.FromContext(ctx)
+
+	if dbCookies, err := loadCookiesFromChromeDB(""); err == nil && len(dbCookies) > 0 {
+		browser.MustSetCookies(dbCookies...)
+		logger.Debug().Int("count", len(dbCookies)).Msg("loaded cookies from chrome profile db")
+		return
+	}
+
 	cookiePath := cookies.GetCookiesFilePath()
 	cookieLoader := cookies.NewLoadCookie(cookiePath)
 
@@ -90,72 +121,44 @@ func loadCookiesForBrowser(browser *rod.Browser) {
 		var cookieData []*proto.NetworkCookie
 		if err := json.Unmarshal(data, &cookieData); err == nil {
 			browser.MustSetCookies(cookieData...)
-			logrus.Debugf("loaded %d cookies from file successfully", len(cookieData))
+			logger.Debug().Int("count", len(cookieData)).Msg("loaded cookies from file")
 		}
 	} else {
-		logrus.Warnf("failed to load cookies: %v", err)
+		logger.Warn().Err(err).Msg("failed to load cookies")
 	}
 }
 
-// getUserChromeDataDir returns the path to the user's Chrome default profile directory
-func getUserChromeDataDir() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		logrus.Warnf("Failed to get user home directory: %v", err)
-		return ""
+// copyUserCookiesToAutomation copies the given Chrome profile's cookies to the
+// automation profile. profileDir is the source profile directory name, e.g.
+// "Default" or "Profile 1" (see browser/profiles); an empty value falls back
+// to "Default".
+func copyUserCookiesToAutomation(automationDir, profileDir string) {
+	if profileDir == "" {
+		profileDir = "Default"
 	}
 
-	// macOS Chrome default profile directory
-	chromeProfileDir := filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome", "Default")
-
-	logrus.Infof("Using Chrome default profile directory: %s", chromeProfileDir)
-	return chromeProfileDir
-}
-
-// getAutomationChromeDataDir returns a separate Chrome data directory for automation
-func getAutomationChromeDataDir() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		logrus.Warnf("Failed to get user home directory: %v", err)
-		return ""
-	}
-
-	// Create a separate Chrome profile for automation to avoid conflicts
-	chromeDataDir := filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome-Automation")
-
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(chromeDataDir, 0755); err != nil {
-		logrus.Warnf("Failed to create Chrome automation directory: %v", err)
-	}
-
-	logrus.Infof("Using Chrome automation data directory: %s", chromeDataDir)
-	return chromeDataDir
-}
-
-// copyUserCookiesToAutomation copies user's Chrome cookies to automation profile
-func copyUserCookiesToAutomation(automationDir string) {
 	userChromeDir := getUserChromeDataDir()
-	userCookiesPath := filepath.Join(userChromeDir, "Default", "Cookies")
-	automationCookiesDir := filepath.Join(automationDir, "Default")
+	userCookiesPath := filepath.Join(userChromeDir, profileDir, "Cookies")
+	automationCookiesDir := filepath.Join(automationDir, profileDir)
 	automationCookiesPath := filepath.Join(automationCookiesDir, "Cookies")
 
-	// Create Default directory in automation profile
+	// Create the profile directory in the automation profile
 	if err := os.MkdirAll(automationCookiesDir, 0755); err != nil {
-		logrus.Warnf("Failed to create automation Default directory: %v", err)
+		log.Warnf("Failed to create automation %s directory: %v", profileDir, err)
 		return
 	}
 
 	// Check if user cookies file exists
 	if _, err := os.Stat(userCookiesPath); os.IsNotExist(err) {
-		logrus.Info("No user cookies file found to copy")
+		log.Info("No user cookies file found to copy")
 		return
 	}
 
 	// Copy cookies file
 	if err := copyFile(userCookiesPath, automationCookiesPath); err != nil {
-		logrus.Warnf("Failed to copy user cookies: %v", err)
+		log.Warnf("Failed to copy user cookies: %v", err)
 	} else {
-		logrus.Info("Successfully copied user cookies to automation profile")
+		log.Info("Successfully copied user cookies to automation profile")
 	}
 }
 
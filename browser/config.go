@@ -0,0 +1,131 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/xpzouying/xiaohongshu-mcp/log"
+)
+
+// Config holds the configuration options for the browser.
+type Config struct {
+	Headless            bool     // Whether to run browser in headless mode
+	UserAgent           string   // Custom user agent string
+	Cookies             string   // JSON string of cookies to set
+	UseSystemChrome     bool     // Whether to use system Chrome instead of default Chromium
+	ChromePath          string   // Explicit Chrome/Chromium binary path, overrides auto-discovery
+	UserDataDir         string   // Explicit Chrome user-data directory, overrides auto-discovery
+	ProfileDir          string   // Chrome profile directory to use, e.g. "Default" or "Profile 1" (see browser/profiles)
+	ExtraArgs           []string // Additional Chrome command-line flags, appended after the hardened default set
+	RemoteDebuggingPort int      // DevTools port to request; 0 asks Chrome to pick one, resolved from the control URL rod hands back
+}
+
+// hardenedChromeFlags are applied to every launch to cut down on background
+// noise and automation tells, unrelated to sandboxing (handled separately via
+// isRunningAsRoot). Each entry is a flag name and an optional value.
+var hardenedChromeFlags = [][2]string{
+	{"--disable-background-networking", ""},
+	{"--disable-features", "site-per-process,TranslateUI"},
+	{"--disable-dev-shm-usage", ""},
+	{"--disable-renderer-backgrounding", ""},
+	{"--force-color-profile", "srgb"},
+	{"--metrics-recording-only", ""},
+	{"--no-first-run", ""},
+	{"--no-startup-window", ""},
+	{"--enable-automation", ""},
+}
+
+// NewConfig creates a new Config with default values.
+func NewConfig(headless bool) Config {
+	return Config{
+		Headless:        headless,
+		UserAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		UseSystemChrome: true, // 默认使用系统 Chrome
+	}
+}
+
+// isSystemChromeAvailable checks if system Chrome is available
+func isSystemChromeAvailable() bool {
+	return findChromePath() != ""
+}
+
+// systemChromeUsable reports whether the system-Chrome launch path in
+// NewBrowserWithConfig has anywhere to point Chrome at: either auto-discovery
+// found a binary, or the caller pinned an explicit ChromePath/UserDataDir,
+// which should be honored even when auto-discovery itself comes up empty.
+func systemChromeUsable(cfg Config) bool {
+	return cfg.ChromePath != "" || cfg.UserDataDir != "" || isSystemChromeAvailable()
+}
+
+// checkChromeBinEnv honors the $CHROME_BIN override shared across all platforms.
+func checkChromeBinEnv() string {
+	if bin := os.Getenv("CHROME_BIN"); bin != "" {
+		if fileExists(bin) {
+			return bin
+		}
+	}
+	return ""
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// getUserChromeDataDir returns the user's Chrome user-data directory, the parent
+// of each profile directory such as "Default" or "Profile 1".
+func getUserChromeDataDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		log.Warnf("Failed to get user home directory: %v", err)
+		return ""
+	}
+
+	var chromeProfileDir string
+	switch runtime.GOOS {
+	case "windows":
+		chromeProfileDir = filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data")
+	case "linux":
+		chromeProfileDir = filepath.Join(homeDir, ".config", "google-chrome")
+	default:
+		chromeProfileDir = filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome")
+	}
+
+	log.Infof("Using Chrome default profile directory: %s", chromeProfileDir)
+	return chromeProfileDir
+}
+
+// getAutomationChromeDataDir returns a separate Chrome data directory for automation,
+// isolated from the user's everyday browsing profile.
+func getAutomationChromeDataDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		log.Warnf("Failed to get user home directory: %v", err)
+		return ""
+	}
+
+	var chromeDataDir string
+	switch runtime.GOOS {
+	case "windows":
+		chromeDataDir = filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome-Automation")
+	case "linux":
+		chromeDataDir = filepath.Join(homeDir, ".config", "google-chrome-automation")
+	default:
+		chromeDataDir = filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome-Automation")
+	}
+
+	if err := os.MkdirAll(chromeDataDir, 0755); err != nil {
+		log.Warnf("Failed to create Chrome automation directory: %v", err)
+	}
+
+	log.Infof("Using Chrome automation data directory: %s", chromeDataDir)
+	return chromeDataDir
+}
+
+// isRunningAsRoot reports whether the process is running as root on Linux, the one
+// platform where Chrome refuses to launch without --no-sandbox.
+func isRunningAsRoot() bool {
+	return runtime.GOOS == "linux" && os.Geteuid() == 0
+}
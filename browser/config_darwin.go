@@ -0,0 +1,23 @@
+//go:build darwin
+
+package browser
+
+// findChromePath finds the path to Chrome on macOS.
+func findChromePath() string {
+	if bin := checkChromeBinEnv(); bin != "" {
+		return bin
+	}
+
+	chromePaths := []string{
+		"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+		"/Applications/Google Chrome Canary.app/Contents/MacOS/Google Chrome Canary",
+		"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	}
+
+	for _, path := range chromePaths {
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package browser
+
+// findChromePath finds the path to Chrome/Chromium on Linux.
+func findChromePath() string {
+	if bin := checkChromeBinEnv(); bin != "" {
+		return bin
+	}
+
+	chromePaths := []string{
+		"/usr/bin/google-chrome",
+		"/usr/bin/google-chrome-stable",
+		"/usr/bin/chromium",
+		"/usr/bin/chromium-browser",
+		"/snap/bin/chromium",
+	}
+
+	for _, path := range chromePaths {
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+//go:build windows
+
+package browser
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// findChromePath finds the path to Chrome on Windows, consulting the registry
+// App Paths key before falling back to well-known install directories.
+func findChromePath() string {
+	if bin := checkChromeBinEnv(); bin != "" {
+		return bin
+	}
+
+	if path := findChromePathFromRegistry(); path != "" {
+		return path
+	}
+
+	candidates := []string{
+		filepath.Join(os.Getenv("ProgramFiles"), "Google", "Chrome", "Application", "chrome.exe"),
+		filepath.Join(os.Getenv("ProgramFiles(x86)"), "Google", "Chrome", "Application", "chrome.exe"),
+		filepath.Join(os.Getenv("LocalAppData"), "Google", "Chrome", "Application", "chrome.exe"),
+	}
+
+	for _, path := range candidates {
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// findChromePathFromRegistry looks up chrome.exe's App Paths entry, checking
+// HKLM before HKCU.
+func findChromePathFromRegistry() string {
+	const keyPath = `SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\chrome.exe`
+
+	for _, root := range []registry.Key{registry.LOCAL_MACHINE, registry.CURRENT_USER} {
+		k, err := registry.OpenKey(root, keyPath, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+
+		path, _, err := k.GetStringValue("")
+		k.Close()
+		if err == nil && fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
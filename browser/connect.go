@@ -0,0 +1,130 @@
+package browser
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/stealth"
+	"github.com/xpzouying/xiaohongshu-mcp/log"
+)
+
+// ErrNoDebuggerFound is returned when the target host has no reachable DevTools
+// debugger, either because /json/version could not be fetched or it returned no
+// webSocketDebuggerUrl.
+var ErrNoDebuggerFound = errors.New("browser: no DevTools debugger found at endpoint")
+
+// ErrConnectRefused is returned when the DevTools websocket endpoint was resolved
+// but the CDP connection itself could not be established.
+var ErrConnectRefused = errors.New("browser: connection to DevTools endpoint refused")
+
+// ConnectOptions configures Connect.
+type ConnectOptions struct {
+	Timeout           time.Duration     // Timeout for resolving /json/version, default 10s; the CDP connection itself is bounded only by ctx
+	SlowMo            time.Duration     // Delay injected between CDP calls, useful for debugging
+	IgnoreHTTPSErrors bool              // Ignore TLS verification errors when resolving /json/version
+	Headers           map[string]string // Extra HTTP headers, sent with both the /json/version resolve and the CDP websocket dial, e.g. for auth proxies in front of the endpoint
+}
+
+// Browser wraps a rod.Browser connected to an already-running Chrome instance,
+// as opposed to one this process launched itself.
+type Browser struct {
+	browser *rod.Browser
+}
+
+// Close closes the remote browser over CDP (Browser.close), which shuts down
+// the Chrome instance Connect attached to.
+func (b *Browser) Close() error {
+	return b.browser.Close()
+}
+
+// NewPage creates a new page with stealth mode enabled.
+func (b *Browser) NewPage() *rod.Page {
+	return stealth.MustPage(b.browser)
+}
+
+// Connect attaches to a remote Chrome instance over CDP. wsEndpoint may be a
+// ws:// DevTools websocket URL, or an http://host:port address, in which case
+// Connect resolves /json/version itself to obtain the webSocketDebuggerUrl.
+//
+// Connect never exits the process on failure: callers get a typed error back
+// and can retry or fall back to launching a local Chrome instead.
+func Connect(ctx context.Context, wsEndpoint string, opts ConnectOptions) (*Browser, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	debuggerURL := wsEndpoint
+	if strings.HasPrefix(wsEndpoint, "http://") || strings.HasPrefix(wsEndpoint, "https://") {
+		resolved, err := resolveDebuggerURL(ctx, wsEndpoint, opts)
+		if err != nil {
+			return nil, err
+		}
+		debuggerURL = resolved
+	}
+
+	rodBrowser := rod.New().Context(ctx).SlowMotion(opts.SlowMo)
+	if len(opts.Headers) > 0 {
+		header := make(http.Header, len(opts.Headers))
+		for k, v := range opts.Headers {
+			header.Set(k, v)
+		}
+		rodBrowser = rodBrowser.Client(cdp.New(debuggerURL).Header(header))
+	} else {
+		rodBrowser = rodBrowser.ControlURL(debuggerURL)
+	}
+
+	if err := rodBrowser.Connect(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnectRefused, err)
+	}
+
+	log.FromContext(ctx).Info().Str("endpoint", wsEndpoint).Msg("connected to remote Chrome")
+
+	return &Browser{browser: rodBrowser}, nil
+}
+
+// resolveDebuggerURL fetches /json/version from an http(s) endpoint and extracts
+// the webSocketDebuggerUrl.
+func resolveDebuggerURL(ctx context.Context, endpoint string, opts ConnectOptions) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/json/version", nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoDebuggerFound, err)
+	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: opts.Timeout}
+	if opts.IgnoreHTTPSErrors {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoDebuggerFound, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: unexpected status %d", ErrNoDebuggerFound, resp.StatusCode)
+	}
+
+	var version struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoDebuggerFound, err)
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", ErrNoDebuggerFound
+	}
+
+	return version.WebSocketDebuggerURL, nil
+}
@@ -0,0 +1,116 @@
+// Package profiles enumerates the Chrome account profiles available on this
+// machine by reading Chrome's own "Local State" file, the same way Chrome
+// itself tracks which profile directory belongs to which signed-in account.
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Profile describes a single Chrome profile directory as recorded in Local
+// State's profile.info_cache.
+type Profile struct {
+	Directory string // e.g. "Default", "Profile 1"
+	Name      string // display name shown in Chrome's profile switcher
+	GaiaName  string // signed-in Google account name, if any
+	Avatar    string // avatar icon identifier
+}
+
+// Registry is the set of profiles discovered in a Local State file.
+type Registry struct {
+	profiles []Profile
+	lastUsed string
+}
+
+// localState mirrors the subset of Chrome's Local State JSON this package cares about.
+type localState struct {
+	Profile struct {
+		LastUsed  string `json:"last_used"`
+		InfoCache map[string]struct {
+			Name       string `json:"name"`
+			GAIAName   string `json:"gaia_name"`
+			AvatarIcon string `json:"avatar_icon"`
+		} `json:"info_cache"`
+	} `json:"profile"`
+}
+
+// Load reads the Local State file from Chrome's default user-data directory.
+func Load() (*Registry, error) {
+	dir, err := defaultChromeDataDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromPath(filepath.Join(dir, "Local State"))
+}
+
+// LoadFromPath reads and parses a Local State file at an explicit path.
+func LoadFromPath(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("profiles: read Local State: %w", err)
+	}
+
+	var state localState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("profiles: parse Local State: %w", err)
+	}
+
+	reg := &Registry{lastUsed: state.Profile.LastUsed}
+	for dir, info := range state.Profile.InfoCache {
+		reg.profiles = append(reg.profiles, Profile{
+			Directory: dir,
+			Name:      info.Name,
+			GaiaName:  info.GAIAName,
+			Avatar:    info.AvatarIcon,
+		})
+	}
+
+	return reg, nil
+}
+
+// List returns every profile found in Local State.
+func (r *Registry) List() []Profile {
+	return r.profiles
+}
+
+// ByName returns the profile whose display name or Gaia account name matches name.
+func (r *Registry) ByName(name string) (Profile, bool) {
+	for _, p := range r.profiles {
+		if p.Name == name || p.GaiaName == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// LastUsed returns the profile Chrome most recently opened, if recorded.
+func (r *Registry) LastUsed() (Profile, bool) {
+	for _, p := range r.profiles {
+		if p.Directory == r.lastUsed {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// defaultChromeDataDir returns the root Chrome user-data directory, the parent
+// of "Local State" and each profile directory, for the current OS.
+func defaultChromeDataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("profiles: get home directory: %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data"), nil
+	case "linux":
+		return filepath.Join(homeDir, ".config", "google-chrome"), nil
+	default:
+		return filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome"), nil
+	}
+}
@@ -0,0 +1,76 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLocalState(t *testing.T, dir, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "Local State")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoadFromPath(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLocalState(t, dir, `{
+		"profile": {
+			"last_used": "Profile 1",
+			"info_cache": {
+				"Default": {"name": "Person 1", "gaia_name": "", "avatar_icon": "chrome://theme/IDR_PROFILE_AVATAR_0"},
+				"Profile 1": {"name": "Work", "gaia_name": "jane@example.com", "avatar_icon": "chrome://theme/IDR_PROFILE_AVATAR_1"}
+			}
+		}
+	}`)
+
+	reg, err := LoadFromPath(path)
+	require.NoError(t, err)
+	assert.Len(t, reg.List(), 2)
+
+	t.Run("ByName matches display name", func(t *testing.T) {
+		p, ok := reg.ByName("Work")
+		require.True(t, ok)
+		assert.Equal(t, "Profile 1", p.Directory)
+	})
+
+	t.Run("ByName matches gaia name", func(t *testing.T) {
+		p, ok := reg.ByName("jane@example.com")
+		require.True(t, ok)
+		assert.Equal(t, "Profile 1", p.Directory)
+	})
+
+	t.Run("ByName misses unknown name", func(t *testing.T) {
+		_, ok := reg.ByName("nobody")
+		assert.False(t, ok)
+	})
+
+	t.Run("LastUsed resolves the recorded directory", func(t *testing.T) {
+		p, ok := reg.LastUsed()
+		require.True(t, ok)
+		assert.Equal(t, "Work", p.Name)
+	})
+}
+
+func TestLoadFromPathLastUsedMissing(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLocalState(t, dir, `{
+		"profile": {
+			"last_used": "Profile 7",
+			"info_cache": {
+				"Default": {"name": "Person 1"}
+			}
+		}
+	}`)
+
+	reg, err := LoadFromPath(path)
+	require.NoError(t, err)
+
+	_, ok := reg.LastUsed()
+	assert.False(t, ok)
+}
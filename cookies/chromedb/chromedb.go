@@ -0,0 +1,145 @@
+// Package chromedb reads cookies directly out of Chrome's on-disk "Cookies"
+// SQLite database, decrypting values the same way Chrome itself does, so
+// callers don't have to hand-export cookies to JSON first.
+package chromedb
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/xpzouying/xiaohongshu-mcp/log"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	pbkdf2Iterations = 1003
+	pbkdf2KeyLen     = 16
+	pbkdf2Salt       = "saltysalt"
+)
+
+// chromeEpoch is the reference point Chrome/Chromium measures expires_utc and
+// similar timestamps from (also Windows' FILETIME epoch).
+var chromeEpoch = time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// row mirrors the subset of Chrome's cookies table this package reads.
+type row struct {
+	HostKey        string
+	Name           string
+	EncryptedValue []byte
+	Path           string
+	ExpiresUTC     int64
+	IsSecure       bool
+	IsHTTPOnly     bool
+	SameSite       int
+}
+
+// ReadCookies opens Chrome's Cookies SQLite database at dbPath, decrypts every
+// cookie whose host is hostSuffix or a subdomain of it (e.g. "xiaohongshu.com"
+// matches "www.xiaohongshu.com" but not "evilxiaohongshu.com"), and returns
+// them in the shape the existing JSON-based cookie loader expects.
+func ReadCookies(dbPath, hostSuffix string) ([]*proto.NetworkCookie, error) {
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, fmt.Errorf("chromedb: open %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(context.Background(),
+		`SELECT host_key, name, encrypted_value, path, expires_utc, is_secure, is_httponly, samesite
+		 FROM cookies WHERE host_key = ? OR host_key LIKE ?`, hostSuffix, "%."+hostSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("chromedb: query cookies: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*proto.NetworkCookie
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.HostKey, &r.Name, &r.EncryptedValue, &r.Path, &r.ExpiresUTC,
+			&r.IsSecure, &r.IsHTTPOnly, &r.SameSite); err != nil {
+			return nil, fmt.Errorf("chromedb: scan cookie row: %w", err)
+		}
+
+		value, err := decryptCookieValue(r.EncryptedValue)
+		if err != nil {
+			log.Warnf("chromedb: skipping cookie %q for %s: %v", r.Name, r.HostKey, err)
+			continue
+		}
+
+		var expires proto.TimeSinceEpoch
+		if r.ExpiresUTC != 0 {
+			expires = proto.TimeSinceEpoch(chromeEpochToTime(r.ExpiresUTC).Unix())
+		}
+
+		out = append(out, &proto.NetworkCookie{
+			Name:     r.Name,
+			Value:    value,
+			Domain:   r.HostKey,
+			Path:     r.Path,
+			Expires:  expires,
+			Secure:   r.IsSecure,
+			HTTPOnly: r.IsHTTPOnly,
+			SameSite: sameSiteFromChrome(r.SameSite),
+		})
+	}
+	return out, rows.Err()
+}
+
+// chromeEpochToTime converts microseconds since the Chrome/Windows epoch
+// (1601-01-01) into a time.Time. A zero value means "no expiry".
+func chromeEpochToTime(chromeMicros int64) time.Time {
+	if chromeMicros == 0 {
+		return time.Time{}
+	}
+	// Offset in microseconds rather than via time.Duration, which overflows
+	// for spans longer than ~292 years.
+	return time.UnixMicro(chromeMicros + chromeEpoch.UnixMicro()).UTC()
+}
+
+func sameSiteFromChrome(v int) proto.NetworkCookieSameSite {
+	switch v {
+	case 1:
+		return proto.NetworkCookieSameSiteLax
+	case 2:
+		return proto.NetworkCookieSameSiteStrict
+	default:
+		return proto.NetworkCookieSameSiteNone
+	}
+}
+
+// decryptAESCBC decrypts ciphertext encrypted with AES-128-CBC under a fixed
+// 16-space IV, the scheme macOS and Linux Chrome both use, and strips the
+// trailing PKCS7 padding.
+func decryptAESCBC(ciphertext, key []byte) (string, error) {
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("chromedb: ciphertext is not a multiple of the block size")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("chromedb: new AES cipher: %w", err)
+	}
+
+	iv := bytes.Repeat([]byte(" "), aes.BlockSize)
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	return string(unpadPKCS7(plaintext)), nil
+}
+
+func unpadPKCS7(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	padLen := int(data[len(data)-1])
+	if padLen <= 0 || padLen > len(data) {
+		return data
+	}
+	return data[:len(data)-padLen]
+}
@@ -0,0 +1,91 @@
+package chromedb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestChromeEpochToTime(t *testing.T) {
+	cases := []struct {
+		name   string
+		micros int64
+		want   time.Time
+	}{
+		{"zero means no expiry", 0, time.Time{}},
+		{"known timestamp", 13343401076000000, time.Date(2023, 11, 2, 12, 17, 56, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := chromeEpochToTime(c.micros)
+			assert.True(t, got.Equal(c.want), "chromeEpochToTime(%d) = %v, want %v", c.micros, got, c.want)
+		})
+	}
+}
+
+func TestUnpadPKCS7(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want []byte
+	}{
+		{"valid padding", []byte("hello\x03\x03\x03"), []byte("hello")},
+		{"full block of padding", bytes.Repeat([]byte{16}, 16), []byte{}},
+		{"empty input", []byte{}, []byte{}},
+		{"pad length exceeds data, left as-is", []byte{1, 2, 99}, []byte{1, 2, 99}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, unpadPKCS7(c.in))
+		})
+	}
+}
+
+func TestDecryptAESCBCRoundTrip(t *testing.T) {
+	key := pbkdf2.Key([]byte("peanuts"), []byte(pbkdf2Salt), pbkdf2Iterations, pbkdf2KeyLen, sha1.New)
+
+	plaintext := []byte("session=abc123")
+	padded := append([]byte{}, plaintext...)
+	padLen := aes.BlockSize - len(padded)%aes.BlockSize
+	padded = append(padded, bytes.Repeat([]byte{byte(padLen)}, padLen)...)
+
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	iv := bytes.Repeat([]byte(" "), aes.BlockSize)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	got, err := decryptAESCBC(ciphertext, key)
+	require.NoError(t, err)
+	assert.Equal(t, string(plaintext), got)
+}
+
+func TestSameSiteFromChrome(t *testing.T) {
+	cases := []struct {
+		name string
+		in   int
+		want proto.NetworkCookieSameSite
+	}{
+		{"lax", 1, proto.NetworkCookieSameSiteLax},
+		{"strict", 2, proto.NetworkCookieSameSiteStrict},
+		{"no restriction", 0, proto.NetworkCookieSameSiteNone},
+		{"unknown value falls back to none", 99, proto.NetworkCookieSameSiteNone},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, sameSiteFromChrome(c.in))
+		})
+	}
+}
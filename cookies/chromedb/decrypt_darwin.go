@@ -0,0 +1,42 @@
+//go:build darwin
+
+package chromedb
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// decryptCookieValue decrypts a v10-prefixed cookie value using the macOS
+// Keychain-backed "Chrome Safe Storage" password.
+func decryptCookieValue(encrypted []byte) (string, error) {
+	if len(encrypted) == 0 {
+		return "", nil
+	}
+	if !bytes.HasPrefix(encrypted, []byte("v10")) {
+		return string(encrypted), nil
+	}
+
+	password, err := chromeSafeStoragePassword()
+	if err != nil {
+		return "", fmt.Errorf("read Chrome Safe Storage password from Keychain: %w", err)
+	}
+
+	key := pbkdf2.Key([]byte(password), []byte(pbkdf2Salt), pbkdf2Iterations, pbkdf2KeyLen, sha1.New)
+	return decryptAESCBC(encrypted[3:], key)
+}
+
+// chromeSafeStoragePassword reads the "Chrome Safe Storage" generic password
+// from the login Keychain.
+func chromeSafeStoragePassword() (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", "Chrome Safe Storage", "-w").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,55 @@
+//go:build linux
+
+package chromedb
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// linuxPeanutsFallbackPassword is the password Chrome itself falls back to
+// when no OS keyring (libsecret) is available to store "Chrome Safe Storage".
+const linuxPeanutsFallbackPassword = "peanuts"
+
+// decryptCookieValue decrypts a v10- or v11-prefixed cookie value. v10 always
+// means Chrome used the "peanuts" fallback password (no keyring was present
+// when the cookie was written); v11 means it used the libsecret-backed
+// "Chrome Safe Storage" password. A DB can contain both, so the key must be
+// selected per-cookie by prefix rather than once for the whole read.
+func decryptCookieValue(encrypted []byte) (string, error) {
+	if len(encrypted) == 0 {
+		return "", nil
+	}
+
+	var password string
+	switch {
+	case bytes.HasPrefix(encrypted, []byte("v10")):
+		password = linuxPeanutsFallbackPassword
+	case bytes.HasPrefix(encrypted, []byte("v11")):
+		secret, err := libsecretPassword()
+		if err != nil || secret == "" {
+			password = linuxPeanutsFallbackPassword
+		} else {
+			password = secret
+		}
+	default:
+		return string(encrypted), nil
+	}
+
+	key := pbkdf2.Key([]byte(password), []byte(pbkdf2Salt), pbkdf2Iterations, pbkdf2KeyLen, sha1.New)
+	return decryptAESCBC(encrypted[3:], key)
+}
+
+// libsecretPassword looks up the "Chrome Safe Storage" secret via secret-tool,
+// the CLI front-end for libsecret.
+func libsecretPassword() (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "application", "chrome").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
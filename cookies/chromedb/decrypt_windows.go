@@ -0,0 +1,119 @@
+//go:build windows
+
+package chromedb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modcrypt32             = windows.NewLazySystemDLL("crypt32.dll")
+	procCryptUnprotectData = modcrypt32.NewProc("CryptUnprotectData")
+)
+
+// dataBlob mirrors Windows' DATA_BLOB struct used by the DPAPI calls.
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+// decryptCookieValue decrypts a v10/v11-prefixed cookie value using the
+// DPAPI-protected AES-256-GCM master key stored in Chrome's Local State.
+func decryptCookieValue(encrypted []byte) (string, error) {
+	if len(encrypted) == 0 {
+		return "", nil
+	}
+	if !bytes.HasPrefix(encrypted, []byte("v10")) && !bytes.HasPrefix(encrypted, []byte("v11")) {
+		return string(encrypted), nil
+	}
+
+	key, err := chromeMasterKey()
+	if err != nil {
+		return "", fmt.Errorf("get DPAPI-protected master key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("new AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("new GCM: %w", err)
+	}
+
+	const nonceSize = 12
+	payload := encrypted[3:]
+	if len(payload) < nonceSize {
+		return "", fmt.Errorf("encrypted value shorter than GCM nonce")
+	}
+	nonce, ciphertext := payload[:nonceSize], payload[nonceSize:]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("GCM open: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// chromeMasterKey reads os_crypt.encrypted_key from Local State and unwraps
+// it with CryptUnprotectData.
+func chromeMasterKey() ([]byte, error) {
+	localStatePath := filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "User Data", "Local State")
+
+	data, err := os.ReadFile(localStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("read Local State: %w", err)
+	}
+
+	var state struct {
+		OSCrypt struct {
+			EncryptedKey string `json:"encrypted_key"`
+		} `json:"os_crypt"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse Local State: %w", err)
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(state.OSCrypt.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted_key: %w", err)
+	}
+	encryptedKey = bytes.TrimPrefix(encryptedKey, []byte("DPAPI"))
+
+	return cryptUnprotectData(encryptedKey)
+}
+
+// cryptUnprotectData unwraps data that was protected with the current user's
+// DPAPI master key (CryptProtectData).
+func cryptUnprotectData(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty DPAPI blob")
+	}
+
+	in := dataBlob{cbData: uint32(len(data)), pbData: &data[0]}
+	var out dataBlob
+
+	r, _, err := procCryptUnprotectData.Call(
+		uintptr(unsafe.Pointer(&in)),
+		0, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("CryptUnprotectData: %w", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.pbData)))
+
+	result := make([]byte, out.cbData)
+	copy(result, unsafe.Slice(out.pbData, out.cbData))
+	return result, nil
+}
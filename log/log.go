@@ -0,0 +1,90 @@
+// Package log is a lightweight structured-logging facade backed by zerolog.
+// It is configured via LOG_LEVEL, LOG_FORMAT (json|console) and LOG_OUTPUT
+// (stderr|file) environment variables, and supports per-request correlation
+// via WithContext/FromContext so every downstream log line can be tied back
+// to the request that triggered it.
+package log
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog"
+)
+
+type ctxKey struct{}
+
+var base = newBase()
+
+// newBase builds the process-wide zerolog.Logger from the environment.
+func newBase() zerolog.Logger {
+	level, err := zerolog.ParseLevel(strings.ToLower(os.Getenv("LOG_LEVEL")))
+	if err != nil {
+		level = zerolog.InfoLevel
+	}
+
+	var out io.Writer = os.Stderr
+	if strings.ToLower(os.Getenv("LOG_OUTPUT")) == "file" {
+		if f, err := os.OpenFile("xiaohongshu-mcp.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			out = f
+		}
+	}
+
+	if strings.ToLower(os.Getenv("LOG_FORMAT")) != "json" {
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	}
+
+	return zerolog.New(out).Level(level).With().Timestamp().Logger()
+}
+
+// NewRequestID generates a ULID used to correlate one request's log lines.
+func NewRequestID() string {
+	return ulid.Make().String()
+}
+
+// WithContext returns a copy of ctx carrying a logger scoped to requestID,
+// to be picked up downstream by FromContext.
+func WithContext(ctx context.Context, requestID string) context.Context {
+	logger := base.With().Str("request_id", requestID).Logger()
+	return context.WithValue(ctx, ctxKey{}, &logger)
+}
+
+// FromContext returns the request-scoped logger attached by WithContext, or
+// the unscoped base logger if ctx carries none.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*zerolog.Logger); ok {
+		return logger
+	}
+	return &base
+}
+
+// The functions below are convenience wrappers around the base logger for
+// call sites that have no request-scoped context to hand.
+
+func Info(msg string) {
+	base.Info().Msg(msg)
+}
+
+func Infof(format string, args ...interface{}) {
+	base.Info().Msgf(format, args...)
+}
+
+func Debugf(format string, args ...interface{}) {
+	base.Debug().Msgf(format, args...)
+}
+
+func Warn(msg string) {
+	base.Warn().Msg(msg)
+}
+
+func Warnf(format string, args ...interface{}) {
+	base.Warn().Msgf(format, args...)
+}
+
+func Error(err error, msg string) {
+	base.Error().Err(err).Msg(msg)
+}
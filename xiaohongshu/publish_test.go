@@ -14,7 +14,7 @@ func TestPublish(t *testing.T) {
 
 	t.Skip("SKIP: 测试发布")
 
-	b := browser.NewBrowser(false)
+	b := browser.NewBrowser(context.Background(), false)
 	// Browser will remain open - no Close() call
 
 	page := b.NewPage()